@@ -0,0 +1,85 @@
+//go:build cgo && !race
+// +build cgo,!race
+
+package crypto
+
+import "testing"
+
+// TestSHA256Allocations asserts that streaming through NewSHA256 incurs no
+// heap allocations per Write/Sum once the hash itself is constructed,
+// mirroring the dev.boringcrypto approach of keeping EVP_MD_CTX inline.
+func TestSHA256Allocations(t *testing.T) {
+	h := NewSHA256()
+	data := make([]byte, 4096)
+	buf := make([]byte, 0, h.Size())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		h.Write(data)
+		buf = h.Sum(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("NewSHA256 streaming allocs = %v, want 0", allocs)
+	}
+}
+
+// TestSHA512Allocations is TestSHA256Allocations for NewSHA512.
+func TestSHA512Allocations(t *testing.T) {
+	h := NewSHA512()
+	data := make([]byte, 4096)
+	buf := make([]byte, 0, h.Size())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		h.Write(data)
+		buf = h.Sum(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("NewSHA512 streaming allocs = %v, want 0", allocs)
+	}
+}
+
+// TestGCMSealAllocations asserts that BoringSSLGCM.Seal, given a dst slice
+// with sufficient spare capacity, performs no heap allocations in the
+// steady state.
+func TestGCMSealAllocations(t *testing.T) {
+	key := make([]byte, 32)
+	aead, err := NewBoringSSLGCM(key)
+	if err != nil {
+		t.Fatalf("NewBoringSSLGCM: %v", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	plaintext := make([]byte, 4096)
+	aad := make([]byte, 24)
+	dst := make([]byte, 0, len(plaintext)+aead.Overhead())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = aead.Seal(dst[:0], nonce, plaintext, aad)
+	})
+	if allocs != 0 {
+		t.Errorf("BoringSSLGCM.Seal allocs = %v, want 0", allocs)
+	}
+}
+
+// TestChaCha20Poly1305SealAllocations is TestGCMSealAllocations for the
+// ChaCha20-Poly1305 AEAD added alongside NewBoringSSLGCM.
+func TestChaCha20Poly1305SealAllocations(t *testing.T) {
+	key := make([]byte, 32)
+	aead, err := NewBoringSSLChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatalf("NewBoringSSLChaCha20Poly1305: %v", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	plaintext := make([]byte, 4096)
+	aad := make([]byte, 24)
+	dst := make([]byte, 0, len(plaintext)+aead.Overhead())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = aead.Seal(dst[:0], nonce, plaintext, aad)
+	})
+	if allocs != 0 {
+		t.Errorf("ChaCha20-Poly1305 Seal allocs = %v, want 0", allocs)
+	}
+}
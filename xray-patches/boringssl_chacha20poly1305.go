@@ -0,0 +1,52 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+// chaCha20Poly1305NonceSize and chaCha20Poly1305TagSize mirror the IETF
+// ChaCha20-Poly1305 construction (RFC 8439): a 12-byte nonce and a 16-byte
+// authentication tag.
+const (
+	chaCha20Poly1305NonceSize = 12
+	chaCha20Poly1305TagSize   = 16
+	chaCha20Poly1305KeySize   = 32
+)
+
+// errOpenChaCha20Poly1305 is returned when authentication fails during Open,
+// matching the opaque "message authentication failed" style used by
+// crypto/cipher's stdlib AEAD implementations so callers cannot
+// distinguish a corrupted tag from a corrupted ciphertext.
+var errOpenChaCha20Poly1305 = errors.New("crypto: chacha20poly1305: message authentication failed")
+
+// NewBoringSSLChaCha20Poly1305 returns a cipher.AEAD implementing IETF
+// ChaCha20-Poly1305 (RFC 8439) backed by BoringSSL's EVP_AEAD interface,
+// sealing and opening directly into the caller's dst (see boringSSLAEAD in
+// boringssl_gcm.go) the same way NewBoringSSLGCM does for AES-GCM. key
+// must be 32 bytes. FIPSOnly mode rejects it, since ChaCha20-Poly1305 is
+// not a FIPS 140-2 approved algorithm; see boringssl_fips.go.
+func NewBoringSSLChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if FIPSOnly() {
+		return nil, ErrNonFIPSAlgorithm
+	}
+	return newBoringSSLChaCha20Poly1305AEAD(key)
+}
+
+// sliceForAppend extends the in slice by n bytes and returns the extended
+// slice plus a slice of just the new bytes, growing the underlying array
+// when dst lacks spare capacity. This is the same append-to-dst idiom used
+// by crypto/cipher's stdlib AEAD implementations.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,84 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+/*
+#cgo LDFLAGS: -lcrypto
+#include <openssl/crypto.h>
+*/
+import "C"
+
+import (
+	"crypto/tls"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNonFIPSAlgorithm is returned by AEAD constructors when
+// crypto.SetFIPSOnly(true) is active and the requested algorithm is not on
+// the FIPS 140-2 approved list.
+var ErrNonFIPSAlgorithm = errors.New("crypto: algorithm is not FIPS 140-2 approved")
+
+// boringSSLFIPSModeLinked is set once at init from BoringSSL_FIPS_mode and
+// records whether the linked libcrypto was actually built as a FIPS
+// module. SetFIPSOnly(true) refuses to enable FIPS-only mode when this is
+// false, since the process would otherwise silently run non-validated
+// crypto while claiming FIPS compliance.
+var boringSSLFIPSModeLinked = BoringSSL_FIPS_mode()
+
+// BoringSSL_FIPS_mode reports whether the linked libcrypto was built in
+// FIPS mode, via BoringSSL's FIPS_mode().
+func BoringSSL_FIPS_mode() bool {
+	return C.FIPS_mode() != 0
+}
+
+var fipsOnly atomic.Bool
+
+// SetFIPSOnly enables or disables FIPS-only mode. When enabled,
+// NewBoringSSLChaCha20Poly1305, NewBoringSSLXChaCha20Poly1305, and (by the
+// same convention) NewBoringSSLGCM / AES*GCMEncrypt refuse any algorithm
+// outside the FIPS 140-2 approved subset, returning ErrNonFIPSAlgorithm.
+//
+// SetFIPSOnly(true) panics if the linked libcrypto was not itself built as
+// a FIPS module: claiming FIPS-only operation on a non-FIPS build would be
+// a silent lie rather than a loud failure.
+func SetFIPSOnly(enabled bool) {
+	if enabled && !boringSSLFIPSModeLinked {
+		panic("crypto: SetFIPSOnly(true): linked libcrypto was not built in FIPS mode")
+	}
+	fipsOnly.Store(enabled)
+}
+
+// FIPSOnly reports whether FIPS-only mode is currently enabled.
+func FIPSOnly() bool {
+	return fipsOnly.Load()
+}
+
+// FIPSApprovedCipherSuites returns the FIPS 140-2 approved TLS 1.2 and
+// 1.3 cipher suite IDs: AES-128-GCM-SHA256 and AES-256-GCM-SHA384, paired
+// with ECDHE (P-256/P-384) key exchange and RSA or ECDSA-P256
+// authentication.
+//
+// This package has no TLS integration layer of its own to restrict — there
+// is no tls.Config construction anywhere in this tree — so nothing calls
+// this automatically. It exists as the extension point a TLS-layer caller
+// should use: when FIPSOnly() is true, build tls.Config.CipherSuites from
+// this slice instead of the Go default list.
+func FIPSApprovedCipherSuites() []uint16 {
+	return []uint16{
+		tls.TLS_AES_128_GCM_SHA256,
+		tls.TLS_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	}
+}
+
+// FIPSApprovedCurves returns the FIPS 140-2 approved ECDHE curves, P-256
+// and P-384. See FIPSApprovedCipherSuites for how a TLS layer should use
+// this under FIPS-only mode.
+func FIPSApprovedCurves() []tls.CurveID {
+	return []tls.CurveID{tls.CurveP256, tls.CurveP384}
+}
@@ -0,0 +1,95 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// withFIPSOnly enables FIPS-only mode for the duration of the test and
+// restores the previous setting afterward, since FIPSOnly is process-wide
+// state shared by every test in the package.
+func withFIPSOnly(t *testing.T, enabled bool) {
+	t.Helper()
+	prev := FIPSOnly()
+	SetFIPSOnly(enabled)
+	t.Cleanup(func() { SetFIPSOnly(prev) })
+}
+
+// TestFIPSOnlyRejectsChaCha20Poly1305 asserts that ChaCha20-Poly1305 and
+// XChaCha20-Poly1305, which are not FIPS 140-2 approved, are refused once
+// FIPS-only mode is enabled.
+func TestFIPSOnlyRejectsChaCha20Poly1305(t *testing.T) {
+	withFIPSOnly(t, true)
+
+	if _, err := NewBoringSSLChaCha20Poly1305(make([]byte, 32)); !errors.Is(err, ErrNonFIPSAlgorithm) {
+		t.Errorf("NewBoringSSLChaCha20Poly1305 error = %v, want ErrNonFIPSAlgorithm", err)
+	}
+	if _, err := NewBoringSSLXChaCha20Poly1305(make([]byte, 32)); !errors.Is(err, ErrNonFIPSAlgorithm) {
+		t.Errorf("NewBoringSSLXChaCha20Poly1305 error = %v, want ErrNonFIPSAlgorithm", err)
+	}
+}
+
+// TestFIPSOnlyAllowsApprovedAlgorithms asserts that AES-GCM, which is FIPS
+// 140-2 approved, still succeeds under FIPS-only mode.
+func TestFIPSOnlyAllowsApprovedAlgorithms(t *testing.T) {
+	withFIPSOnly(t, true)
+
+	if _, err := NewBoringSSLGCM(make([]byte, 16)); err != nil {
+		t.Errorf("NewBoringSSLGCM(128-bit) under FIPS-only: %v", err)
+	}
+	if _, err := NewBoringSSLGCM(make([]byte, 32)); err != nil {
+		t.Errorf("NewBoringSSLGCM(256-bit) under FIPS-only: %v", err)
+	}
+}
+
+// TestFIPSOnlyDisabledAllowsChaCha20Poly1305 asserts that ChaCha20-Poly1305
+// works normally when FIPS-only mode is off (the default).
+func TestFIPSOnlyDisabledAllowsChaCha20Poly1305(t *testing.T) {
+	withFIPSOnly(t, false)
+
+	if _, err := NewBoringSSLChaCha20Poly1305(make([]byte, 32)); err != nil {
+		t.Errorf("NewBoringSSLChaCha20Poly1305 with FIPS-only disabled: %v", err)
+	}
+}
+
+// TestFIPSApprovedCipherSuites asserts that every returned suite is a
+// GCM suite backed by AES, with no ChaCha20-Poly1305 or CBC entries.
+func TestFIPSApprovedCipherSuites(t *testing.T) {
+	suites := FIPSApprovedCipherSuites()
+	if len(suites) == 0 {
+		t.Fatal("FIPSApprovedCipherSuites returned no suites")
+	}
+	byID := make(map[uint16]tls.CipherSuite)
+	for _, cs := range tls.CipherSuites() {
+		byID[cs.ID] = *cs
+	}
+	for _, id := range suites {
+		cs, ok := byID[id]
+		if !ok {
+			t.Errorf("suite 0x%04x not recognized by crypto/tls", id)
+			continue
+		}
+		if !strings.Contains(cs.Name, "GCM") {
+			t.Errorf("suite %s is not a GCM suite", cs.Name)
+		}
+	}
+}
+
+// TestFIPSApprovedCurves asserts the curve list is exactly P-256 and P-384.
+func TestFIPSApprovedCurves(t *testing.T) {
+	curves := FIPSApprovedCurves()
+	want := map[tls.CurveID]bool{tls.CurveP256: true, tls.CurveP384: true}
+	if len(curves) != len(want) {
+		t.Fatalf("FIPSApprovedCurves = %v, want exactly P-256 and P-384", curves)
+	}
+	for _, c := range curves {
+		if !want[c] {
+			t.Errorf("unexpected curve %v in FIPSApprovedCurves", c)
+		}
+	}
+}
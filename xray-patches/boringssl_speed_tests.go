@@ -0,0 +1,41 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestRunSpeedBenchmarkCLI_Speed asserts that passing -speed runs the
+// benchmark and reports handled=true.
+func TestRunSpeedBenchmarkCLI_Speed(t *testing.T) {
+	var buf bytes.Buffer
+	handled, err := RunSpeedBenchmarkCLI([]string{"-speed"}, &buf)
+	if !handled {
+		t.Fatal("RunSpeedBenchmarkCLI did not report handled for -speed")
+	}
+	if err != nil {
+		t.Fatalf("RunSpeedBenchmarkCLI: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("RunSpeedBenchmarkCLI wrote no output")
+	}
+}
+
+// TestRunSpeedBenchmarkCLI_NoFlag asserts that the benchmark is skipped,
+// and nothing is written, when -speed is absent.
+func TestRunSpeedBenchmarkCLI_NoFlag(t *testing.T) {
+	var buf bytes.Buffer
+	handled, err := RunSpeedBenchmarkCLI(nil, &buf)
+	if handled {
+		t.Fatal("RunSpeedBenchmarkCLI reported handled with no flags")
+	}
+	if err != nil {
+		t.Fatalf("RunSpeedBenchmarkCLI: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("RunSpeedBenchmarkCLI wrote output when not handled")
+	}
+}
@@ -0,0 +1,284 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+/*
+#cgo LDFLAGS: -lcrypto
+#include <stdlib.h>
+#include <string.h>
+#include <openssl/aead.h>
+
+static int x_evp_aead_xchacha20_poly1305_supported(void) {
+	return EVP_aead_xchacha20_poly1305() != NULL;
+}
+
+static int x_evp_aead_xchacha20_poly1305_seal(const uint8_t *key, const uint8_t *nonce,
+		const uint8_t *in, size_t in_len, const uint8_t *ad, size_t ad_len,
+		uint8_t *out, size_t *out_len, size_t max_out_len) {
+	EVP_AEAD_CTX ctx;
+	if (!EVP_AEAD_CTX_init(&ctx, EVP_aead_xchacha20_poly1305(), key, 32, 16, NULL)) {
+		return 0;
+	}
+	int ok = EVP_AEAD_CTX_seal(&ctx, out, out_len, max_out_len, nonce, 24, in, in_len, ad, ad_len);
+	EVP_AEAD_CTX_cleanup(&ctx);
+	return ok;
+}
+
+static int x_evp_aead_xchacha20_poly1305_open(const uint8_t *key, const uint8_t *nonce,
+		const uint8_t *in, size_t in_len, const uint8_t *ad, size_t ad_len,
+		uint8_t *out, size_t *out_len, size_t max_out_len) {
+	EVP_AEAD_CTX ctx;
+	if (!EVP_AEAD_CTX_init(&ctx, EVP_aead_xchacha20_poly1305(), key, 32, 16, NULL)) {
+		return 0;
+	}
+	int ok = EVP_AEAD_CTX_open(&ctx, out, out_len, max_out_len, nonce, 24, in, in_len, ad, ad_len);
+	EVP_AEAD_CTX_cleanup(&ctx);
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"unsafe"
+)
+
+// XChaCha20-Poly1305 (draft-irtf-cfrg-xchacha, libsodium/Tink compatible)
+// extends IETF ChaCha20-Poly1305 with a 24-byte nonce: the first 16 bytes
+// are run through HChaCha20 with the key to derive a 32-byte subkey, and
+// the remaining 8 bytes become the low 8 bytes of the inner 12-byte nonce
+// (the high 4 bytes are zero).
+const (
+	xChaCha20Poly1305NonceSize = 24
+	xChaCha20Poly1305TagSize   = 16
+	xChaCha20Poly1305KeySize   = 32
+
+	hChaCha20NonceSize = 16
+)
+
+// boringSSLXChaCha20SupportedAtLink records whether the linked BoringSSL's
+// EVP_aead_xchacha20_poly1305 returns a non-NULL AEAD; when it does not,
+// Seal and Open use the pure-Go HChaCha20 + ChaCha20Poly1305Encrypt path.
+// The C helpers above call EVP_aead_xchacha20_poly1305 directly, so this
+// file only builds and links against a libcrypto that declares and exports
+// that function; older BoringSSL snapshots without it are not supported.
+var boringSSLXChaCha20SupportedAtLink = C.x_evp_aead_xchacha20_poly1305_supported() != 0
+
+// boringSSLXChaCha20Poly1305 implements cipher.AEAD for XChaCha20-Poly1305.
+type boringSSLXChaCha20Poly1305 struct {
+	key [xChaCha20Poly1305KeySize]byte
+}
+
+// NewBoringSSLXChaCha20Poly1305 returns a cipher.AEAD implementing
+// XChaCha20-Poly1305 backed by BoringSSL's EVP_aead_xchacha20_poly1305,
+// falling back to HChaCha20 subkey derivation plus the existing
+// ChaCha20-Poly1305 implementation when that function returns NULL (see
+// boringSSLXChaCha20SupportedAtLink). key must be 32 bytes. NonceSize() is
+// 24, Overhead() is 16.
+func NewBoringSSLXChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if FIPSOnly() {
+		return nil, ErrNonFIPSAlgorithm
+	}
+	if len(key) != xChaCha20Poly1305KeySize {
+		return nil, errors.New("crypto: bad XChaCha20-Poly1305 key length")
+	}
+	a := &boringSSLXChaCha20Poly1305{}
+	copy(a.key[:], key)
+	return a, nil
+}
+
+func (a *boringSSLXChaCha20Poly1305) NonceSize() int { return xChaCha20Poly1305NonceSize }
+
+func (a *boringSSLXChaCha20Poly1305) Overhead() int { return xChaCha20Poly1305TagSize }
+
+func (a *boringSSLXChaCha20Poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != xChaCha20Poly1305NonceSize {
+		panic("crypto: bad nonce length passed to XChaCha20-Poly1305")
+	}
+	if boringSSLXChaCha20SupportedAtLink {
+		ciphertext, err := boringSSLXChaCha20Poly1305SealNative(a.key, nonce, plaintext, additionalData)
+		if err != nil {
+			panic("crypto: " + err.Error())
+		}
+		ret, out := sliceForAppend(dst, len(ciphertext))
+		copy(out, ciphertext)
+		return ret
+	}
+
+	subkey, innerNonce := xChaCha20Poly1305DeriveSubkey(a.key, nonce)
+	ciphertext, tag, err := ChaCha20Poly1305Encrypt(subkey[:], innerNonce[:], plaintext, additionalData)
+	if err != nil {
+		panic("crypto: " + err.Error())
+	}
+	ret, out := sliceForAppend(dst, len(ciphertext)+len(tag))
+	copy(out, ciphertext)
+	copy(out[len(ciphertext):], tag)
+	return ret
+}
+
+func (a *boringSSLXChaCha20Poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != xChaCha20Poly1305NonceSize {
+		return nil, errors.New("crypto: bad nonce length passed to XChaCha20-Poly1305")
+	}
+	if len(ciphertext) < xChaCha20Poly1305TagSize {
+		return nil, errOpenChaCha20Poly1305
+	}
+
+	if boringSSLXChaCha20SupportedAtLink {
+		plaintext, err := boringSSLXChaCha20Poly1305OpenNative(a.key, nonce, ciphertext, additionalData)
+		if err != nil {
+			return nil, errOpenChaCha20Poly1305
+		}
+		ret, out := sliceForAppend(dst, len(plaintext))
+		copy(out, plaintext)
+		return ret, nil
+	}
+
+	body := ciphertext[:len(ciphertext)-xChaCha20Poly1305TagSize]
+	tag := ciphertext[len(ciphertext)-xChaCha20Poly1305TagSize:]
+
+	subkey, innerNonce := xChaCha20Poly1305DeriveSubkey(a.key, nonce)
+	plaintext, err := ChaCha20Poly1305Decrypt(subkey[:], innerNonce[:], body, tag, additionalData)
+	if err != nil {
+		return nil, errOpenChaCha20Poly1305
+	}
+
+	ret, out := sliceForAppend(dst, len(plaintext))
+	copy(out, plaintext)
+	return ret, nil
+}
+
+// boringSSLXChaCha20Poly1305SealNative seals using BoringSSL's native
+// EVP_aead_xchacha20_poly1305, avoiding the manual HChaCha20 derivation
+// step entirely (BoringSSL performs it internally).
+func boringSSLXChaCha20Poly1305SealNative(key [xChaCha20Poly1305KeySize]byte, nonce, plaintext, additionalData []byte) ([]byte, error) {
+	out := make([]byte, len(plaintext)+xChaCha20Poly1305TagSize)
+	var outLen C.size_t
+	ok := C.x_evp_aead_xchacha20_poly1305_seal(
+		(*C.uint8_t)(unsafe.Pointer(&key[0])),
+		(*C.uint8_t)(unsafe.Pointer(&nonce[0])),
+		boringSSLBytePtr(plaintext), C.size_t(len(plaintext)),
+		boringSSLBytePtr(additionalData), C.size_t(len(additionalData)),
+		(*C.uint8_t)(unsafe.Pointer(&out[0])), &outLen, C.size_t(len(out)),
+	)
+	if ok == 0 {
+		return nil, errors.New("crypto: BoringSSL_EVP_AEAD_CTX_seal failed for XChaCha20-Poly1305")
+	}
+	return out[:outLen], nil
+}
+
+// boringSSLXChaCha20Poly1305OpenNative opens using BoringSSL's native
+// EVP_aead_xchacha20_poly1305.
+func boringSSLXChaCha20Poly1305OpenNative(key [xChaCha20Poly1305KeySize]byte, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	out := make([]byte, len(ciphertext))
+	var outLen C.size_t
+	ok := C.x_evp_aead_xchacha20_poly1305_open(
+		(*C.uint8_t)(unsafe.Pointer(&key[0])),
+		(*C.uint8_t)(unsafe.Pointer(&nonce[0])),
+		boringSSLBytePtr(ciphertext), C.size_t(len(ciphertext)),
+		boringSSLBytePtr(additionalData), C.size_t(len(additionalData)),
+		(*C.uint8_t)(unsafe.Pointer(&out[0])), &outLen, C.size_t(len(out)),
+	)
+	if ok == 0 {
+		return nil, errors.New("crypto: BoringSSL_EVP_AEAD_CTX_open failed for XChaCha20-Poly1305")
+	}
+	return out[:outLen], nil
+}
+
+// XChaCha20Poly1305Encrypt encrypts plaintext with XChaCha20-Poly1305,
+// returning the ciphertext and 16-byte authentication tag separately, in
+// the same style as ChaCha20Poly1305Encrypt. key must be 32 bytes and
+// nonce must be 24 bytes.
+func XChaCha20Poly1305Encrypt(key, nonce, plaintext, aad []byte) (ciphertext, tag []byte, err error) {
+	aead, err := NewBoringSSLXChaCha20Poly1305(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(nonce) != xChaCha20Poly1305NonceSize {
+		return nil, nil, errors.New("crypto: bad nonce length passed to XChaCha20Poly1305Encrypt")
+	}
+	sealed := aead.Seal(nil, nonce, plaintext, aad)
+	return sealed[:len(sealed)-xChaCha20Poly1305TagSize], sealed[len(sealed)-xChaCha20Poly1305TagSize:], nil
+}
+
+// XChaCha20Poly1305Decrypt decrypts ciphertext encrypted by
+// XChaCha20Poly1305Encrypt (or any compatible XChaCha20-Poly1305
+// implementation), verifying tag and aad.
+func XChaCha20Poly1305Decrypt(key, nonce, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := NewBoringSSLXChaCha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != xChaCha20Poly1305NonceSize {
+		return nil, errors.New("crypto: bad nonce length passed to XChaCha20Poly1305Decrypt")
+	}
+	combined := make([]byte, 0, len(ciphertext)+len(tag))
+	combined = append(combined, ciphertext...)
+	combined = append(combined, tag...)
+	return aead.Open(nil, nonce, combined, aad)
+}
+
+// xChaCha20Poly1305DeriveSubkey splits a 24-byte XChaCha20-Poly1305 nonce
+// into the 16-byte HChaCha20 input and the remaining 8 bytes, returning the
+// derived 32-byte subkey and the 12-byte inner ChaCha20-Poly1305 nonce
+// (four zero bytes followed by the 8 remaining nonce bytes).
+func xChaCha20Poly1305DeriveSubkey(key [xChaCha20Poly1305KeySize]byte, nonce []byte) (subkey [32]byte, innerNonce [12]byte) {
+	var hNonce [hChaCha20NonceSize]byte
+	copy(hNonce[:], nonce[:hChaCha20NonceSize])
+	subkey = hChaCha20(key, hNonce)
+	copy(innerNonce[4:], nonce[hChaCha20NonceSize:])
+	return subkey, innerNonce
+}
+
+// hChaCha20 computes the HChaCha20 intermediate hash used to derive an
+// XChaCha20 subkey from a 32-byte key and 16-byte nonce, as specified by
+// the XChaCha draft. Unlike the ChaCha20 block function, the output is the
+// permuted state itself with no feed-forward addition of the input state.
+func hChaCha20(key [32]byte, nonce [16]byte) [32]byte {
+	var state [16]uint32
+	state[0] = 0x61707865
+	state[1] = 0x3320646e
+	state[2] = 0x79622d32
+	state[3] = 0x6b206574
+	for i := 0; i < 8; i++ {
+		state[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	for i := 0; i < 4; i++ {
+		state[12+i] = binary.LittleEndian.Uint32(nonce[i*4:])
+	}
+
+	for i := 0; i < 10; i++ {
+		hChaCha20QuarterRound(&state, 0, 4, 8, 12)
+		hChaCha20QuarterRound(&state, 1, 5, 9, 13)
+		hChaCha20QuarterRound(&state, 2, 6, 10, 14)
+		hChaCha20QuarterRound(&state, 3, 7, 11, 15)
+		hChaCha20QuarterRound(&state, 0, 5, 10, 15)
+		hChaCha20QuarterRound(&state, 1, 6, 11, 12)
+		hChaCha20QuarterRound(&state, 2, 7, 8, 13)
+		hChaCha20QuarterRound(&state, 3, 4, 9, 14)
+	}
+
+	var out [32]byte
+	for i, w := range []uint32{state[0], state[1], state[2], state[3], state[12], state[13], state[14], state[15]} {
+		binary.LittleEndian.PutUint32(out[i*4:], w)
+	}
+	return out
+}
+
+func hChaCha20QuarterRound(state *[16]uint32, a, b, c, d int) {
+	state[a] += state[b]
+	state[d] ^= state[a]
+	state[d] = state[d]<<16 | state[d]>>16
+	state[c] += state[d]
+	state[b] ^= state[c]
+	state[b] = state[b]<<12 | state[b]>>20
+	state[a] += state[b]
+	state[d] ^= state[a]
+	state[d] = state[d]<<8 | state[d]>>24
+	state[c] += state[d]
+	state[b] ^= state[c]
+	state[b] = state[b]<<7 | state[b]>>25
+}
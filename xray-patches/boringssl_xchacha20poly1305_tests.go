@@ -0,0 +1,78 @@
+//go:build cgo
+// +build cgo
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestHChaCha20Vector checks HChaCha20 against the test vector from
+// section 2.2 of the XChaCha draft (draft-irtf-cfrg-xchacha-03).
+func TestHChaCha20Vector(t *testing.T) {
+	key := mustDecodeHex(t, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	nonce := mustDecodeHex(t, "000000090000004a0000000031415927")
+	want := mustDecodeHex(t, "82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc")
+
+	var keyArr [32]byte
+	var nonceArr [16]byte
+	copy(keyArr[:], key)
+	copy(nonceArr[:], nonce)
+
+	got := hChaCha20(keyArr, nonceArr)
+	if !bytes.Equal(got[:], want) {
+		t.Fatalf("hChaCha20 = %x, want %x", got, want)
+	}
+}
+
+// TestXChaCha20Poly1305Vector checks NewBoringSSLXChaCha20Poly1305 against
+// the AEAD test vector from appendix A.3 of the XChaCha draft
+// (draft-irtf-cfrg-xchacha-03), which is also used by libsodium and Tink.
+func TestXChaCha20Poly1305Vector(t *testing.T) {
+	key := mustDecodeHex(t, "808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	nonce := mustDecodeHex(t, "404142434445464748494a4b4c4d4e4f5051525354555657")
+	aad := mustDecodeHex(t, "50515253c0c1c2c3c4c5c6c7")
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	wantCiphertext := mustDecodeHex(t, "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff921f9664c97637da9768812f615c68b13b52ec0875924c1c7987947deafd8780acf49")
+
+	aead, err := NewBoringSSLXChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatalf("NewBoringSSLXChaCha20Poly1305: %v", err)
+	}
+	if aead.NonceSize() != 24 {
+		t.Errorf("NonceSize() = %d, want 24", aead.NonceSize())
+	}
+	if aead.Overhead() != 16 {
+		t.Errorf("Overhead() = %d, want 16", aead.Overhead())
+	}
+
+	got := aead.Seal(nil, nonce, plaintext, aad)
+	if !bytes.Equal(got, wantCiphertext) {
+		t.Fatalf("Seal = %x, want %x", got, wantCiphertext)
+	}
+
+	opened, err := aead.Open(nil, nonce, got, aad)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("Open = %q, want %q", opened, plaintext)
+	}
+
+	tampered := append([]byte(nil), got...)
+	tampered[0] ^= 0xFF
+	if _, err := aead.Open(nil, nonce, tampered, aad); err == nil {
+		t.Fatal("Open accepted tampered ciphertext")
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q): %v", s, err)
+	}
+	return b
+}